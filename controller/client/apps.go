@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+// AppsService accesses app resources.
+type AppsService struct {
+	c *Client
+}
+
+// List returns all apps.
+func (s *AppsService) List(ctx context.Context) ([]*ct.App, error) {
+	var apps []*ct.App
+	return apps, s.c.get(ctx, "/apps", &apps)
+}
+
+// ListPage returns a page of apps matching opts, along with cursors to the
+// adjacent pages.
+func (s *AppsService) ListPage(ctx context.Context, opts ListOptions) (*Page[*ct.App], error) {
+	return listPage[*ct.App](ctx, s.c, "/apps", opts)
+}
+
+// Iter returns an iterator over all apps matching opts, fetching subsequent
+// pages transparently as the caller advances it.
+func (s *AppsService) Iter(ctx context.Context, opts ListOptions) *Iterator[*ct.App] {
+	return newIterator(func(ctx context.Context) (*Page[*ct.App], error) {
+		return s.ListPage(ctx, opts)
+	})
+}
+
+// Get returns details for the specified app.
+func (s *AppsService) Get(ctx context.Context, appID string) (*ct.App, error) {
+	app := &ct.App{}
+	return app, s.c.get(ctx, fmt.Sprintf("/apps/%s", appID), app)
+}
+
+// Create creates a new app.
+func (s *AppsService) Create(ctx context.Context, app *ct.App) error {
+	return s.c.post(ctx, "/apps", app, app)
+}
+
+// Delete deletes an app.
+func (s *AppsService) Delete(ctx context.Context, appID string) error {
+	return s.c.delete(ctx, fmt.Sprintf("/apps/%s", appID))
+}
+
+// GetRelease returns the current release of an app.
+func (s *AppsService) GetRelease(ctx context.Context, appID string) (*ct.Release, error) {
+	release := &ct.Release{}
+	return release, s.c.get(ctx, fmt.Sprintf("/apps/%s/release", appID), release)
+}
+
+// SetRelease sets the specified release as the current release for an app.
+func (s *AppsService) SetRelease(ctx context.Context, appID, releaseID string) error {
+	return s.c.put(ctx, fmt.Sprintf("/apps/%s/release", appID), &ct.Release{ID: releaseID}, nil)
+}
+
+// AppList returns a list of all apps.
+//
+// Deprecated: use Client.Apps().List instead.
+func (c *Client) AppList() ([]*ct.App, error) {
+	return c.AppListContext(context.Background())
+}
+
+// AppListContext acts like AppList, but bounds the request to ctx.
+//
+// Deprecated: use Client.Apps().List instead.
+func (c *Client) AppListContext(ctx context.Context) ([]*ct.App, error) {
+	return c.Apps().List(ctx)
+}
+
+// GetApp returns details for the specified app.
+//
+// Deprecated: use Client.Apps().Get instead.
+func (c *Client) GetApp(appID string) (*ct.App, error) {
+	return c.GetAppContext(context.Background(), appID)
+}
+
+// GetAppContext acts like GetApp, but bounds the request to ctx.
+//
+// Deprecated: use Client.Apps().Get instead.
+func (c *Client) GetAppContext(ctx context.Context, appID string) (*ct.App, error) {
+	return c.Apps().Get(ctx, appID)
+}
+
+// CreateApp creates a new app.
+//
+// Deprecated: use Client.Apps().Create instead.
+func (c *Client) CreateApp(app *ct.App) error {
+	return c.CreateAppContext(context.Background(), app)
+}
+
+// CreateAppContext acts like CreateApp, but bounds the request to ctx.
+//
+// Deprecated: use Client.Apps().Create instead.
+func (c *Client) CreateAppContext(ctx context.Context, app *ct.App) error {
+	return c.Apps().Create(ctx, app)
+}
+
+// DeleteApp deletes an app.
+//
+// Deprecated: use Client.Apps().Delete instead.
+func (c *Client) DeleteApp(appID string) error {
+	return c.DeleteAppContext(context.Background(), appID)
+}
+
+// DeleteAppContext acts like DeleteApp, but bounds the request to ctx.
+//
+// Deprecated: use Client.Apps().Delete instead.
+func (c *Client) DeleteAppContext(ctx context.Context, appID string) error {
+	return c.Apps().Delete(ctx, appID)
+}
+
+// GetAppRelease returns the current release of an app.
+//
+// Deprecated: use Client.Apps().GetRelease instead.
+func (c *Client) GetAppRelease(appID string) (*ct.Release, error) {
+	return c.GetAppReleaseContext(context.Background(), appID)
+}
+
+// GetAppReleaseContext acts like GetAppRelease, but bounds the request to ctx.
+//
+// Deprecated: use Client.Apps().GetRelease instead.
+func (c *Client) GetAppReleaseContext(ctx context.Context, appID string) (*ct.Release, error) {
+	return c.Apps().GetRelease(ctx, appID)
+}
+
+// SetAppRelease sets the specified release as the current release for an app.
+//
+// Deprecated: use Client.Apps().SetRelease instead.
+func (c *Client) SetAppRelease(appID, releaseID string) error {
+	return c.SetAppReleaseContext(context.Background(), appID, releaseID)
+}
+
+// SetAppReleaseContext acts like SetAppRelease, but bounds the request to ctx.
+//
+// Deprecated: use Client.Apps().SetRelease instead.
+func (c *Client) SetAppReleaseContext(ctx context.Context, appID, releaseID string) error {
+	return c.Apps().SetRelease(ctx, appID, releaseID)
+}
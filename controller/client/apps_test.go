@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	c, err := NewClientWithTransport(ts.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("NewClientWithTransport: %s", err)
+	}
+	return c, ts
+}
+
+func TestAppsServiceList(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/apps" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*ct.App{{ID: "app1"}, {ID: "app2"}})
+	})
+
+	apps, err := c.Apps().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 2 || apps[0].ID != "app1" || apps[1].ID != "app2" {
+		t.Fatalf("unexpected apps: %+v", apps)
+	}
+}
+
+func TestAppsServiceGet(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/apps/app1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&ct.App{ID: "app1", Name: "foo"})
+	})
+
+	app, err := c.Apps().Get(context.Background(), "app1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.Name != "foo" {
+		t.Fatalf("unexpected app: %+v", app)
+	}
+}
+
+func TestAppsServiceCreate(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/apps" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var app ct.App
+		json.NewDecoder(r.Body).Decode(&app)
+		app.ID = "generated"
+		json.NewEncoder(w).Encode(&app)
+	})
+
+	app := &ct.App{Name: "foo"}
+	if err := c.Apps().Create(context.Background(), app); err != nil {
+		t.Fatal(err)
+	}
+	if app.ID != "generated" {
+		t.Fatalf("expected app to be populated from the response, got %+v", app)
+	}
+}
+
+func TestAppsServiceDelete(t *testing.T) {
+	var deleted bool
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/apps/app1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		deleted = true
+	})
+
+	if err := c.Apps().Delete(context.Background(), "app1"); err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Fatal("expected DELETE request to be sent")
+	}
+}
+
+func TestAppListIsThinWrapper(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]*ct.App{{ID: "app1"}})
+	})
+
+	apps, err := c.AppListContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].ID != "app1" {
+		t.Fatalf("unexpected apps: %+v", apps)
+	}
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+// ArtifactsService accesses artifact resources.
+type ArtifactsService struct {
+	c *Client
+}
+
+// List returns all artifacts.
+func (s *ArtifactsService) List(ctx context.Context) ([]*ct.Artifact, error) {
+	var artifacts []*ct.Artifact
+	return artifacts, s.c.get(ctx, "/artifacts", &artifacts)
+}
+
+// ListPage returns a page of artifacts matching opts, along with cursors to
+// the adjacent pages.
+func (s *ArtifactsService) ListPage(ctx context.Context, opts ListOptions) (*Page[*ct.Artifact], error) {
+	return listPage[*ct.Artifact](ctx, s.c, "/artifacts", opts)
+}
+
+// Iter returns an iterator over all artifacts matching opts, fetching
+// subsequent pages transparently as the caller advances it.
+func (s *ArtifactsService) Iter(ctx context.Context, opts ListOptions) *Iterator[*ct.Artifact] {
+	return newIterator(func(ctx context.Context) (*Page[*ct.Artifact], error) {
+		return s.ListPage(ctx, opts)
+	})
+}
+
+// Get returns details for the specified artifact.
+func (s *ArtifactsService) Get(ctx context.Context, artifactID string) (*ct.Artifact, error) {
+	artifact := &ct.Artifact{}
+	return artifact, s.c.get(ctx, fmt.Sprintf("/artifacts/%s", artifactID), artifact)
+}
+
+// Create creates a new artifact.
+func (s *ArtifactsService) Create(ctx context.Context, artifact *ct.Artifact) error {
+	return s.c.post(ctx, "/artifacts", artifact, artifact)
+}
+
+// ArtifactList returns a list of all artifacts.
+//
+// Deprecated: use Client.Artifacts().List instead.
+func (c *Client) ArtifactList() ([]*ct.Artifact, error) {
+	return c.ArtifactListContext(context.Background())
+}
+
+// ArtifactListContext acts like ArtifactList, but bounds the request to ctx.
+//
+// Deprecated: use Client.Artifacts().List instead.
+func (c *Client) ArtifactListContext(ctx context.Context) ([]*ct.Artifact, error) {
+	return c.Artifacts().List(ctx)
+}
+
+// GetArtifact returns details for the specified artifact.
+//
+// Deprecated: use Client.Artifacts().Get instead.
+func (c *Client) GetArtifact(artifactID string) (*ct.Artifact, error) {
+	return c.GetArtifactContext(context.Background(), artifactID)
+}
+
+// GetArtifactContext acts like GetArtifact, but bounds the request to ctx.
+//
+// Deprecated: use Client.Artifacts().Get instead.
+func (c *Client) GetArtifactContext(ctx context.Context, artifactID string) (*ct.Artifact, error) {
+	return c.Artifacts().Get(ctx, artifactID)
+}
+
+// CreateArtifact creates a new artifact.
+//
+// Deprecated: use Client.Artifacts().Create instead.
+func (c *Client) CreateArtifact(artifact *ct.Artifact) error {
+	return c.CreateArtifactContext(context.Background(), artifact)
+}
+
+// CreateArtifactContext acts like CreateArtifact, but bounds the request to ctx.
+//
+// Deprecated: use Client.Artifacts().Create instead.
+func (c *Client) CreateArtifactContext(ctx context.Context, artifact *ct.Artifact) error {
+	return c.Artifacts().Create(ctx, artifact)
+}
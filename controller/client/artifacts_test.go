@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+func TestArtifactsServiceList(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/artifacts" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*ct.Artifact{{ID: "artifact1"}})
+	})
+
+	artifacts, err := c.Artifacts().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifacts) != 1 || artifacts[0].ID != "artifact1" {
+		t.Fatalf("unexpected artifacts: %+v", artifacts)
+	}
+}
+
+func TestArtifactsServiceGet(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/artifacts/artifact1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&ct.Artifact{ID: "artifact1"})
+	})
+
+	artifact, err := c.Artifacts().Get(context.Background(), "artifact1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if artifact.ID != "artifact1" {
+		t.Fatalf("unexpected artifact: %+v", artifact)
+	}
+}
@@ -1,10 +1,15 @@
 // Package controller provides a client for the controller API.
+//
+// Client exposes both a flat, method-per-endpoint API (kept for backwards
+// compatibility; see the Deprecated notices on its methods) and a
+// per-resource service API reached through accessors such as Apps(),
+// Releases(), and Jobs(), which is where new functionality is added.
 package controller
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/base64"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,16 +17,12 @@ import (
 	"net"
 	"net/http"
 	"net/url"
-	"strings"
-	"time"
 
 	ct "github.com/flynn/flynn/controller/types"
-	"github.com/flynn/flynn/controller/utils"
 	"github.com/flynn/flynn/discoverd/client"
 	"github.com/flynn/flynn/discoverd/client/dialer"
 	"github.com/flynn/flynn/pkg/pinned"
 	"github.com/flynn/flynn/pkg/rpcplus"
-	"github.com/flynn/flynn/router/types"
 )
 
 // NewClient creates a new Client pointing at uri and using key for
@@ -37,9 +38,9 @@ func NewClient(uri, key string) (*Client, error) {
 	c := &Client{
 		url:  uri,
 		addr: u.Host,
-		HTTP: http.DefaultClient,
 		key:  key,
 	}
+	base := http.DefaultTransport
 	if u.Scheme == "discoverd+http" {
 		if err := discoverd.Connect(""); err != nil {
 			return nil, err
@@ -47,10 +48,11 @@ func NewClient(uri, key string) (*Client, error) {
 		dialer := dialer.New(discoverd.DefaultClient, nil)
 		c.dial = dialer.Dial
 		c.dialClose = dialer
-		c.HTTP = &http.Client{Transport: &http.Transport{Dial: c.dial}}
+		base = &http.Transport{Dial: c.dial}
 		u.Scheme = "http"
 		c.url = u.String()
 	}
+	c.HTTP = &http.Client{Transport: &BasicAuthTransport{Key: key, Base: base}}
 	return c, nil
 }
 
@@ -70,13 +72,71 @@ func NewClientWithPin(uri, key string, pin []byte) (*Client, error) {
 	c.addr = u.Host
 	u.Scheme = "http"
 	c.url = u.String()
-	c.HTTP = &http.Client{Transport: &http.Transport{Dial: c.dial}}
+	c.HTTP = &http.Client{Transport: &BasicAuthTransport{Key: key, Base: &http.Transport{Dial: c.dial}}}
 	return c, nil
 }
 
+// NewClientWithCert acts like NewClientWithPin, but authenticates the
+// connection itself with an mTLS client certificate, layered on top of the
+// existing pinned.Config dialer. If key is non-empty it is sent as well,
+// allowing a gradual migration from key-based to certificate-based auth;
+// pass an empty key to rely on the certificate alone.
+func NewClientWithCert(uri, key string, cert tls.Certificate, pin []byte) (*Client, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	pinCfg := &pinned.Config{Pin: pin, Config: &tls.Config{Certificates: []tls.Certificate{cert}}}
+	c := &Client{
+		dial: pinCfg.Dial,
+		key:  key,
+	}
+	if _, port, _ := net.SplitHostPort(u.Host); port == "" {
+		u.Host += ":443"
+	}
+	c.addr = u.Host
+	u.Scheme = "http"
+	c.url = u.String()
+	base := &http.Transport{Dial: c.dial}
+	var rt http.RoundTripper = base
+	if key != "" {
+		rt = &BasicAuthTransport{Key: key, Base: base}
+	}
+	c.HTTP = &http.Client{Transport: rt}
+	return c, nil
+}
+
+// NewClientWithTransport creates a new Client pointing at uri, authenticating
+// requests with rt instead of the built-in basic-auth key. This lets
+// operators rotate credentials, source short-lived tokens from a secrets
+// store (see OAuth2Transport), or federate auth entirely, without forking
+// the client. uri must not use the discoverd+http scheme; use NewClient for
+// discoverd-based resolution.
+func NewClientWithTransport(uri string, rt http.RoundTripper) (*Client, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "discoverd+http" {
+		return nil, errors.New("controller: discoverd+http is not supported by NewClientWithTransport, use NewClient")
+	}
+	return &Client{
+		url:  uri,
+		addr: u.Host,
+		HTTP: &http.Client{Transport: rt},
+	}, nil
+}
+
 // Client is a client for the controller API.
 type Client struct {
-	url  string
+	url string
+	// key is the legacy basic-auth key. Regular HTTP requests are
+	// authenticated via HTTP.Transport instead (see BasicAuthTransport,
+	// OAuth2Transport); key is retained only for the paths that don't go
+	// through an http.RoundTripper (the raw rpcplus connection used by
+	// Formations().Subscribe/StreamFormations and the hijacked connection
+	// used by Jobs().RunAttached), and is used there only when
+	// HTTP.Transport does not implement Auther (see authHeader).
 	key  string
 	addr string
 	HTTP *http.Client
@@ -85,6 +145,31 @@ type Client struct {
 	dialClose io.Closer
 }
 
+// Apps returns a service for accessing app resources.
+func (c *Client) Apps() *AppsService { return &AppsService{c} }
+
+// Releases returns a service for accessing release resources.
+func (c *Client) Releases() *ReleasesService { return &ReleasesService{c} }
+
+// Artifacts returns a service for accessing artifact resources.
+func (c *Client) Artifacts() *ArtifactsService { return &ArtifactsService{c} }
+
+// Formations returns a service for accessing formation resources.
+func (c *Client) Formations() *FormationsService { return &FormationsService{c} }
+
+// Jobs returns a service for accessing job resources.
+func (c *Client) Jobs() *JobsService { return &JobsService{c} }
+
+// Routes returns a service for accessing route resources.
+func (c *Client) Routes() *RoutesService { return &RoutesService{c} }
+
+// Providers returns a service for accessing provider (and their resource)
+// resources.
+func (c *Client) Providers() *ProvidersService { return &ProvidersService{c} }
+
+// Keys returns a service for accessing ssh public key resources.
+func (c *Client) Keys() *KeysService { return &KeysService{c} }
+
 // Close closes the underlying transport connection.
 func (c *Client) Close() error {
 	if c.dialClose != nil {
@@ -96,12 +181,57 @@ func (c *Client) Close() error {
 // ErrNotFound is returned when a resource is not found (HTTP status 404).
 var ErrNotFound = errors.New("controller: not found")
 
+// authHeader returns the header that c.HTTP's transport would set to
+// authenticate a regular request, for use by the raw rpcplus dial and
+// hijacked-connection paths that can't go through http.Client.Do. If the
+// transport doesn't implement Auther (a custom RoundTripper passed to
+// NewClientWithTransport that doesn't need one, e.g. because auth happens at
+// the TLS layer), it falls back to the legacy basic-auth key.
+func (c *Client) authHeader() (http.Header, error) {
+	req := &http.Request{Header: make(http.Header)}
+	if a, ok := c.HTTP.Transport.(Auther); ok {
+		if err := a.SetAuth(req); err != nil {
+			return nil, err
+		}
+		return req.Header, nil
+	}
+	req.SetBasicAuth("", c.key)
+	return req.Header, nil
+}
+
 func toJSON(v interface{}) (io.Reader, error) {
 	data, err := json.Marshal(v)
 	return bytes.NewBuffer(data), err
 }
 
-func (c *Client) rawReq(method, path string, header http.Header, in, out interface{}) (*http.Response, error) {
+// closeOnDone spawns a goroutine that closes c as soon as ctx is done,
+// aborting any blocking read/write in progress on it. The returned stop
+// function must be called once the caller is done with c (typically via
+// whatever Close method wraps c) so the goroutine doesn't leak for the
+// lifetime of ctx.
+func closeOnDone(ctx context.Context, c io.Closer) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+	var stopped bool
+	return func() {
+		if stopped {
+			return
+		}
+		stopped = true
+		close(done)
+	}
+}
+
+func (c *Client) rawReq(ctx context.Context, method, path string, header http.Header, in, out interface{}) (*http.Response, error) {
 	var payload io.Reader
 	switch v := in.(type) {
 	case io.Reader:
@@ -115,7 +245,14 @@ func (c *Client) rawReq(method, path string, header http.Header, in, out interfa
 		}
 	}
 
-	req, err := http.NewRequest(method, c.url+path, payload)
+	reqURL := c.url + path
+	if u, err := url.Parse(path); err == nil && u.Host != "" {
+		// path is already an absolute URL (e.g. a Link header value
+		// pointing at a different host than c.url), so use it as-is
+		// rather than concatenating it onto c.url.
+		reqURL = path
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +263,6 @@ func (c *Client) rawReq(method, path string, header http.Header, in, out interfa
 		header.Set("Content-Type", "application/json")
 	}
 	req.Header = header
-	req.SetBasicAuth("", c.key)
 	res, err := c.HTTP.Do(req)
 	if err != nil {
 		return nil, err
@@ -158,352 +294,40 @@ func (c *Client) rawReq(method, path string, header http.Header, in, out interfa
 	return res, nil
 }
 
-func (c *Client) send(method, path string, in, out interface{}) error {
-	_, err := c.rawReq(method, path, nil, in, out)
+func (c *Client) send(ctx context.Context, method, path string, in, out interface{}) error {
+	_, err := c.rawReq(ctx, method, path, nil, in, out)
 	return err
 }
 
-func (c *Client) put(path string, in, out interface{}) error {
-	return c.send("PUT", path, in, out)
+func (c *Client) put(ctx context.Context, path string, in, out interface{}) error {
+	return c.send(ctx, "PUT", path, in, out)
 }
 
-func (c *Client) post(path string, in, out interface{}) error {
-	return c.send("POST", path, in, out)
+func (c *Client) post(ctx context.Context, path string, in, out interface{}) error {
+	return c.send(ctx, "POST", path, in, out)
 }
 
-func (c *Client) get(path string, out interface{}) error {
-	_, err := c.rawReq("GET", path, nil, nil, out)
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	_, err := c.rawReq(ctx, "GET", path, nil, nil, out)
 	return err
 }
 
-func (c *Client) delete(path string) error {
-	res, err := c.rawReq("DELETE", path, nil, nil, nil)
+func (c *Client) delete(ctx context.Context, path string) error {
+	res, err := c.rawReq(ctx, "DELETE", path, nil, nil, nil)
 	if err == nil {
 		res.Body.Close()
 	}
 	return err
 }
 
-// FormationUpdates is a wrapper around a Chan channel, allowing us to close
-// the stream.
-type FormationUpdates struct {
-	Chan <-chan *ct.ExpandedFormation
-
-	conn net.Conn
-}
-
-// Close closes the underlying stream.
-func (u *FormationUpdates) Close() error {
-	if u.conn == nil {
-		return nil
-	}
-	return u.conn.Close()
-}
-
-// StreamFormations returns a FormationUpdates stream. If since is not nil, only
-// retrieves formation updates since the specified time.
-func (c *Client) StreamFormations(since *time.Time) (*FormationUpdates, *error) {
-	if since == nil {
-		s := time.Unix(0, 0)
-		since = &s
-	}
-	dial := c.dial
-	if dial == nil {
-		dial = net.Dial
-	}
-	ch := make(chan *ct.ExpandedFormation)
-	conn, err := dial("tcp", c.addr)
-	if err != nil {
-		close(ch)
-		return &FormationUpdates{ch, conn}, &err
-	}
-	header := make(http.Header)
-	header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+c.key)))
-	client, err := rpcplus.NewHTTPClient(conn, rpcplus.DefaultRPCPath, header)
-	if err != nil {
-		close(ch)
-		return &FormationUpdates{ch, conn}, &err
-	}
-	return &FormationUpdates{ch, conn}, &client.StreamGo("Controller.StreamFormations", since, ch).Error
-}
-
-// CreateArtifact creates a new artifact.
-func (c *Client) CreateArtifact(artifact *ct.Artifact) error {
-	return c.post("/artifacts", artifact, artifact)
-}
-
-// CreateRelease creates a new release.
-func (c *Client) CreateRelease(release *ct.Release) error {
-	return c.post("/releases", release, release)
-}
-
-// CreateApp creates a new app.
-func (c *Client) CreateApp(app *ct.App) error {
-	return c.post("/apps", app, app)
-}
-
-// DeleteApp deletes an app.
-func (c *Client) DeleteApp(appID string) error {
-	return c.delete(fmt.Sprintf("/apps/%s", appID))
-}
-
-// CreateProvider cretes a new provider.
-func (c *Client) CreateProvider(provider *ct.Provider) error {
-	return c.post("/providers", provider, provider)
-}
-
-func (c *Client) GetProvider(providerID string) (*ct.Provider, error) {
-	provider := &ct.Provider{}
-	return provider, c.get(fmt.Sprintf("/providers/%s", providerID), provider)
-}
-
-// ProvisionResource uses a provider to provision a new resource for the
-// application. Returns details about the resource.
-func (c *Client) ProvisionResource(req *ct.ResourceReq) (*ct.Resource, error) {
-	if req.ProviderID == "" {
-		return nil, errors.New("controller: missing provider id")
-	}
-	res := &ct.Resource{}
-	err := c.post(fmt.Sprintf("/providers/%s/resources", req.ProviderID), req, res)
-	return res, err
-}
-
-func (c *Client) GetResource(providerID, resourceID string) (*ct.Resource, error) {
-	res := &ct.Resource{}
-	err := c.get(fmt.Sprintf("/providers/%s/resources/%s", providerID, resourceID), res)
-	return res, err
-}
-
-// PutResource updates a resource.
-func (c *Client) PutResource(resource *ct.Resource) error {
-	if resource.ID == "" || resource.ProviderID == "" {
-		return errors.New("controller: missing id and/or provider id")
-	}
-	return c.put(fmt.Sprintf("/providers/%s/resources/%s", resource.ProviderID, resource.ID), resource, resource)
-}
-
-// PutFormation updates an existing formation.
-func (c *Client) PutFormation(formation *ct.Formation) error {
-	if formation.AppID == "" || formation.ReleaseID == "" {
-		return errors.New("controller: missing app id and/or release id")
-	}
-	return c.put(fmt.Sprintf("/apps/%s/formations/%s", formation.AppID, formation.ReleaseID), formation, formation)
-}
-
-// PutJob updates an existing job.
-func (c *Client) PutJob(job *ct.Job) error {
-	if job.ID == "" || job.AppID == "" {
-		return errors.New("controller: missing job id and/or app id")
-	}
-	return c.put(fmt.Sprintf("/apps/%s/jobs/%s", job.AppID, job.ID), job, job)
-}
-
-// DeleteJob kills a specific job id under the specified app.
-func (c *Client) DeleteJob(appID, jobID string) error {
-	return c.delete(fmt.Sprintf("/apps/%s/jobs/%s", appID, jobID))
-}
-
-// SetAppRelease sets the specified release as the current release for an app.
-func (c *Client) SetAppRelease(appID, releaseID string) error {
-	return c.put(fmt.Sprintf("/apps/%s/release", appID), &ct.Release{ID: releaseID}, nil)
-}
-
-// GetAppRelease returns the current release of an app.
-func (c *Client) GetAppRelease(appID string) (*ct.Release, error) {
-	release := &ct.Release{}
-	return release, c.get(fmt.Sprintf("/apps/%s/release", appID), release)
-}
-
-// RouteList returns all routes for an app.
-func (c *Client) RouteList(appID string) ([]*router.Route, error) {
-	var routes []*router.Route
-	return routes, c.get(fmt.Sprintf("/apps/%s/routes", appID), &routes)
-}
-
-// GetRoute returns details for the routeID under the specified app.
-func (c *Client) GetRoute(appID string, routeID string) (*router.Route, error) {
-	route := &router.Route{}
-	return route, c.get(fmt.Sprintf("/apps/%s/routes/%s", appID, routeID), route)
-}
-
-// CreateRoute creates a new route for the specified app.
-func (c *Client) CreateRoute(appID string, route *router.Route) error {
-	return c.post(fmt.Sprintf("/apps/%s/routes", appID), route, route)
-}
-
-// DeleteRoute deletes a route under the specified app.
-func (c *Client) DeleteRoute(appID string, routeID string) error {
-	return c.delete(fmt.Sprintf("/apps/%s/routes/%s", appID, routeID))
-}
-
-// GetFormation returns details for the specified formation under app and
-// release.
-func (c *Client) GetFormation(appID, releaseID string) (*ct.Formation, error) {
-	formation := &ct.Formation{}
-	return formation, c.get(fmt.Sprintf("/apps/%s/formations/%s", appID, releaseID), formation)
-}
-
-// GetRelease returns details for the specified release.
-func (c *Client) GetRelease(releaseID string) (*ct.Release, error) {
-	release := &ct.Release{}
-	return release, c.get(fmt.Sprintf("/releases/%s", releaseID), release)
-}
-
-// GetArtifact returns details for the specified artifact.
-func (c *Client) GetArtifact(artifactID string) (*ct.Artifact, error) {
-	artifact := &ct.Artifact{}
-	return artifact, c.get(fmt.Sprintf("/artifacts/%s", artifactID), artifact)
-}
-
-// GetApp returns details for the specified app.
-func (c *Client) GetApp(appID string) (*ct.App, error) {
-	app := &ct.App{}
-	return app, c.get(fmt.Sprintf("/apps/%s", appID), app)
-}
-
-type sseDecoder struct {
-	*bufio.Reader
-}
-
-// Decode finds the next "data" field and decodes it into v
-func (dec *sseDecoder) Decode(v interface{}) error {
-	for {
-		line, err := dec.ReadBytes('\n')
-		if err != nil {
-			return err
-		}
-		if bytes.HasPrefix(line, []byte("data: ")) {
-			data := bytes.TrimPrefix(line, []byte("data: "))
-			return json.Unmarshal(data, v)
-		}
-	}
-}
-
-// JobEventStream is a wrapper around an Events channel, allowing us to close
-// the stream.
-type JobEventStream struct {
-	Events chan *ct.JobEvent
-	body   io.ReadCloser
-}
-
-// Close closes the underlying stream.
-func (s *JobEventStream) Close() {
-	s.body.Close()
-}
-
-// StreamJobEvents returns a JobEventStream for an app.
-func (c *Client) StreamJobEvents(appID string) (*JobEventStream, error) {
-	res, err := c.rawReq("GET", fmt.Sprintf("/apps/%s/jobs", appID), http.Header{"Accept": []string{"text/event-stream"}}, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	stream := &JobEventStream{Events: make(chan *ct.JobEvent), body: res.Body}
-	go func() {
-		defer close(stream.Events)
-		dec := &sseDecoder{bufio.NewReader(stream.body)}
-		for {
-			event := &ct.JobEvent{}
-			if err := dec.Decode(event); err != nil {
-				return
-			}
-			stream.Events <- event
-		}
-	}()
-	return stream, nil
-}
-
-// GetJobLog returns a ReadCloser stream of the job with id of jobID, running
-// under appID. If tail is true, new log lines are streamed after the buffered
-// log.
-func (c *Client) GetJobLog(appID, jobID string, tail bool) (io.ReadCloser, error) {
-	path := fmt.Sprintf("/apps/%s/jobs/%s/log", appID, jobID)
-	if tail {
-		path += "?tail=true"
-	}
-	res, err := c.rawReq("GET", path, nil, nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	return res.Body, nil
-}
-
-// RunJobAttached runs a new job under the specified app, attaching to the job
-// and returning a ReadWriteCloser stream, which can then be used for
-// communicating with the job.
-func (c *Client) RunJobAttached(appID string, job *ct.NewJob) (utils.ReadWriteCloser, error) {
-	data, err := toJSON(job)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/apps/%s/jobs", c.url, appID), data)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/vnd.flynn.attach")
-	req.SetBasicAuth("", c.key)
-	var dial rpcplus.DialFunc
-	if c.dial != nil {
-		dial = c.dial
-	}
-	res, rwc, err := utils.HijackRequest(req, dial)
-	if err != nil {
-		res.Body.Close()
-		return nil, err
-	}
-	return rwc, nil
-}
-
-// RunJobDetached runs a new job under the specified app, returning the job's
-// details.
-func (c *Client) RunJobDetached(appID string, req *ct.NewJob) (*ct.Job, error) {
-	job := &ct.Job{}
-	return job, c.post(fmt.Sprintf("/apps/%s/jobs", appID), req, job)
-}
-
-// JobList returns a list of all jobs.
-func (c *Client) JobList(appID string) ([]*ct.Job, error) {
-	var jobs []*ct.Job
-	return jobs, c.get(fmt.Sprintf("/apps/%s/jobs", appID), &jobs)
-}
-
-// AppList returns a list of all apps.
-func (c *Client) AppList() ([]*ct.App, error) {
-	var apps []*ct.App
-	return apps, c.get("/apps", &apps)
-}
-
-// KeyList returns a list of all ssh public keys added.
-func (c *Client) KeyList() ([]*ct.Key, error) {
-	var keys []*ct.Key
-	return keys, c.get("/keys", &keys)
-}
-
-func (c *Client) ArtifactList() ([]*ct.Artifact, error) {
-	var artifacts []*ct.Artifact
-	return artifacts, c.get("/artifacts", &artifacts)
-}
-
-func (c *Client) ReleaseList() ([]*ct.Release, error) {
-	var releases []*ct.Release
-	return releases, c.get("/releases", &releases)
-}
-
-// CreateKey uploads pubKey as the ssh public key.
-func (c *Client) CreateKey(pubKey string) (*ct.Key, error) {
-	key := &ct.Key{}
-	return key, c.post("/keys", &ct.Key{Key: pubKey}, key)
-}
-
-func (c *Client) GetKey(keyID string) (*ct.Key, error) {
-	key := &ct.Key{}
-	return key, c.get(fmt.Sprintf("/keys/%s", keyID), key)
-}
-
-// DeleteKey deletes a key with the specified id.
-func (c *Client) DeleteKey(id string) error {
-	return c.delete("/keys/" + strings.Replace(id, ":", "", -1))
+// ctxReadCloser wraps an io.ReadCloser, stopping its associated
+// context-cancellation watcher once the caller closes it.
+type ctxReadCloser struct {
+	io.ReadCloser
+	stop func()
 }
 
-// ProviderList returns a list of all providers.
-func (c *Client) ProviderList() ([]*ct.Provider, error) {
-	var providers []*ct.Provider
-	return providers, c.get("/providers", &providers)
+func (c *ctxReadCloser) Close() error {
+	c.stop()
+	return c.ReadCloser.Close()
 }
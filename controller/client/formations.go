@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flynn/flynn/controller/client/stream"
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+// FormationsService accesses formation resources.
+type FormationsService struct {
+	c *Client
+}
+
+// Get returns details for the specified formation under app and release.
+func (s *FormationsService) Get(ctx context.Context, appID, releaseID string) (*ct.Formation, error) {
+	formation := &ct.Formation{}
+	return formation, s.c.get(ctx, fmt.Sprintf("/apps/%s/formations/%s", appID, releaseID), formation)
+}
+
+// Put updates an existing formation.
+func (s *FormationsService) Put(ctx context.Context, formation *ct.Formation) error {
+	if formation.AppID == "" || formation.ReleaseID == "" {
+		return errors.New("controller: missing app id and/or release id")
+	}
+	return s.c.put(ctx, fmt.Sprintf("/apps/%s/formations/%s", formation.AppID, formation.ReleaseID), formation, formation)
+}
+
+// Subscribe returns a reconnecting, resumable stream of formation updates;
+// see Client.SubscribeFormations.
+func (s *FormationsService) Subscribe(ctx context.Context, since *time.Time, filter stream.Filter) *stream.EventStream[*ct.ExpandedFormation] {
+	return s.c.SubscribeFormations(ctx, since, filter)
+}
+
+// GetFormation returns details for the specified formation under app and
+// release.
+//
+// Deprecated: use Client.Formations().Get instead.
+func (c *Client) GetFormation(appID, releaseID string) (*ct.Formation, error) {
+	return c.GetFormationContext(context.Background(), appID, releaseID)
+}
+
+// GetFormationContext acts like GetFormation, but bounds the request to ctx.
+//
+// Deprecated: use Client.Formations().Get instead.
+func (c *Client) GetFormationContext(ctx context.Context, appID, releaseID string) (*ct.Formation, error) {
+	return c.Formations().Get(ctx, appID, releaseID)
+}
+
+// PutFormation updates an existing formation.
+//
+// Deprecated: use Client.Formations().Put instead.
+func (c *Client) PutFormation(formation *ct.Formation) error {
+	return c.PutFormationContext(context.Background(), formation)
+}
+
+// PutFormationContext acts like PutFormation, but bounds the request to ctx.
+//
+// Deprecated: use Client.Formations().Put instead.
+func (c *Client) PutFormationContext(ctx context.Context, formation *ct.Formation) error {
+	return c.Formations().Put(ctx, formation)
+}
+
+// FormationUpdates is a wrapper around a Chan channel, allowing us to close
+// the stream. It is implemented on top of Client.SubscribeFormations, so,
+// unlike in earlier releases, it now reconnects automatically with backoff
+// on a dropped connection rather than ending the stream.
+//
+// Deprecated: use Client.SubscribeFormations / Client.Formations().Subscribe
+// instead, which additionally expose Filter and Err().
+type FormationUpdates struct {
+	Chan <-chan *ct.ExpandedFormation
+
+	es *stream.EventStream[*ct.ExpandedFormation]
+}
+
+// Close closes the underlying stream.
+func (u *FormationUpdates) Close() error {
+	return u.es.Close()
+}
+
+// StreamFormations returns a FormationUpdates stream. If since is not nil, only
+// retrieves formation updates since the specified time.
+//
+// Deprecated: use Client.SubscribeFormations instead.
+func (c *Client) StreamFormations(since *time.Time) (*FormationUpdates, *error) {
+	return c.StreamFormationsContext(context.Background(), since)
+}
+
+// StreamFormationsContext acts like StreamFormations, but bounds the
+// underlying stream to ctx: cancelling ctx stops it and closes Chan.
+//
+// Deprecated: use Client.SubscribeFormations instead.
+func (c *Client) StreamFormationsContext(ctx context.Context, since *time.Time) (*FormationUpdates, *error) {
+	es := c.SubscribeFormations(ctx, since, stream.Filter{})
+	ch := make(chan *ct.ExpandedFormation)
+	var err error
+	go func() {
+		defer close(ch)
+		for formation := range es.Events {
+			ch <- formation
+		}
+		err = es.Err()
+	}()
+	// err is only meaningful once Chan is closed: it's written before the
+	// deferred close(ch) above runs, so a caller that waits for Chan to
+	// close before reading it is guaranteed to see the final value.
+	return &FormationUpdates{Chan: ch, es: es}, &err
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+func TestJobsServiceList(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/apps/app1/jobs" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*ct.Job{{ID: "job1", AppID: "app1"}})
+	})
+
+	jobs, err := c.Jobs().List(context.Background(), "app1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "job1" {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+}
+
+func TestJobsServiceRunDetached(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/apps/app1/jobs" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&ct.Job{ID: "job1", AppID: "app1"})
+	})
+
+	job, err := c.Jobs().RunDetached(context.Background(), "app1", &ct.NewJob{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "job1" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+}
+
+func TestGetAsyncJobDone(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/jobs/guid1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&AsyncJob{GUID: "guid1", State: AsyncJobStateComplete})
+	})
+
+	job, err := c.GetAsyncJob(context.Background(), "guid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !job.Done() {
+		t.Fatalf("expected job to be done: %+v", job)
+	}
+}
+
+func TestWaitForAsyncJobPollsUntilDone(t *testing.T) {
+	var attempts int
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		state := AsyncJobStateProcessing
+		if attempts >= 3 {
+			state = AsyncJobStateComplete
+		}
+		json.NewEncoder(w).Encode(&AsyncJob{GUID: "guid1", State: state})
+	})
+
+	job, err := c.WaitForAsyncJob(context.Background(), "guid1", WaitOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !job.Done() || attempts < 3 {
+		t.Fatalf("expected job to complete after polling, got %+v after %d attempts", job, attempts)
+	}
+}
+
+func TestWaitForAsyncJobReturnsErrorOnFailure(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&AsyncJob{GUID: "guid1", State: AsyncJobStateFailed, Errors: []string{"boom"}})
+	})
+
+	_, err := c.WaitForAsyncJob(context.Background(), "guid1", WaitOptions{PollInterval: time.Millisecond})
+	if err == nil {
+		t.Fatal("expected an error for a failed job")
+	}
+}
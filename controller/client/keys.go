@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+// KeysService accesses ssh public key resources.
+type KeysService struct {
+	c *Client
+}
+
+// List returns a list of all ssh public keys added.
+func (s *KeysService) List(ctx context.Context) ([]*ct.Key, error) {
+	var keys []*ct.Key
+	return keys, s.c.get(ctx, "/keys", &keys)
+}
+
+// Get returns details for the specified key.
+func (s *KeysService) Get(ctx context.Context, keyID string) (*ct.Key, error) {
+	key := &ct.Key{}
+	return key, s.c.get(ctx, fmt.Sprintf("/keys/%s", keyID), key)
+}
+
+// Create uploads pubKey as the ssh public key.
+func (s *KeysService) Create(ctx context.Context, pubKey string) (*ct.Key, error) {
+	key := &ct.Key{}
+	return key, s.c.post(ctx, "/keys", &ct.Key{Key: pubKey}, key)
+}
+
+// Delete deletes a key with the specified id.
+func (s *KeysService) Delete(ctx context.Context, id string) error {
+	return s.c.delete(ctx, "/keys/"+strings.Replace(id, ":", "", -1))
+}
+
+// KeyList returns a list of all ssh public keys added.
+//
+// Deprecated: use Client.Keys().List instead.
+func (c *Client) KeyList() ([]*ct.Key, error) {
+	return c.KeyListContext(context.Background())
+}
+
+// KeyListContext acts like KeyList, but bounds the request to ctx.
+//
+// Deprecated: use Client.Keys().List instead.
+func (c *Client) KeyListContext(ctx context.Context) ([]*ct.Key, error) {
+	return c.Keys().List(ctx)
+}
+
+// GetKey returns details for the specified key.
+//
+// Deprecated: use Client.Keys().Get instead.
+func (c *Client) GetKey(keyID string) (*ct.Key, error) {
+	return c.GetKeyContext(context.Background(), keyID)
+}
+
+// GetKeyContext acts like GetKey, but bounds the request to ctx.
+//
+// Deprecated: use Client.Keys().Get instead.
+func (c *Client) GetKeyContext(ctx context.Context, keyID string) (*ct.Key, error) {
+	return c.Keys().Get(ctx, keyID)
+}
+
+// CreateKey uploads pubKey as the ssh public key.
+//
+// Deprecated: use Client.Keys().Create instead.
+func (c *Client) CreateKey(pubKey string) (*ct.Key, error) {
+	return c.CreateKeyContext(context.Background(), pubKey)
+}
+
+// CreateKeyContext acts like CreateKey, but bounds the request to ctx.
+//
+// Deprecated: use Client.Keys().Create instead.
+func (c *Client) CreateKeyContext(ctx context.Context, pubKey string) (*ct.Key, error) {
+	return c.Keys().Create(ctx, pubKey)
+}
+
+// DeleteKey deletes a key with the specified id.
+//
+// Deprecated: use Client.Keys().Delete instead.
+func (c *Client) DeleteKey(id string) error {
+	return c.DeleteKeyContext(context.Background(), id)
+}
+
+// DeleteKeyContext acts like DeleteKey, but bounds the request to ctx.
+//
+// Deprecated: use Client.Keys().Delete instead.
+func (c *Client) DeleteKeyContext(ctx context.Context, id string) error {
+	return c.Keys().Delete(ctx, id)
+}
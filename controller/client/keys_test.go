@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+func TestKeysServiceCreate(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/keys" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&ct.Key{ID: "aa:bb", Key: "ssh-rsa ..."})
+	})
+
+	key, err := c.Keys().Create(context.Background(), "ssh-rsa ...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != "aa:bb" {
+		t.Fatalf("unexpected key: %+v", key)
+	}
+}
+
+func TestKeysServiceDeleteStripsColons(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/keys/aabb" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	if err := c.Keys().Delete(context.Background(), "aa:bb"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions narrows and pages a List call. The zero value lists the
+// first page with the server's default page size and no filtering.
+type ListOptions struct {
+	// Page is the 1-indexed page number to fetch.
+	Page int
+	// PerPage bounds how many items a page contains.
+	PerPage int
+	// Since and Until, if set, restrict results to items created in
+	// that window.
+	Since *time.Time
+	Until *time.Time
+	// Filter restricts results to items whose fields match the given
+	// values, e.g. {"release_id": releaseID}.
+	Filter map[string]string
+	// Sort is a field name to sort by, optionally prefixed with "-" for
+	// descending order (e.g. "-created_at").
+	Sort string
+}
+
+func (o ListOptions) query() url.Values {
+	v := url.Values{}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	if o.Since != nil {
+		v.Set("since", o.Since.Format(time.RFC3339))
+	}
+	if o.Until != nil {
+		v.Set("until", o.Until.Format(time.RFC3339))
+	}
+	if o.Sort != "" {
+		v.Set("sort", o.Sort)
+	}
+	for field, val := range o.Filter {
+		v.Set("filter["+field+"]", val)
+	}
+	return v
+}
+
+// Page is one page of a List call, with cursors to the adjacent pages
+// driven by the response's Link header (RFC 5988, as used by GitHub's
+// API), so a controller with thousands of items doesn't need to be loaded
+// into memory at once.
+type Page[T any] struct {
+	Items []T
+
+	c        *Client
+	nextPath string
+	prevPath string
+}
+
+// HasNext reports whether a next page is available.
+func (p *Page[T]) HasNext() bool { return p.nextPath != "" }
+
+// HasPrev reports whether a previous page is available.
+func (p *Page[T]) HasPrev() bool { return p.prevPath != "" }
+
+// Next fetches the next page.
+func (p *Page[T]) Next(ctx context.Context) (*Page[T], error) {
+	if p.nextPath == "" {
+		return nil, errors.New("controller: no next page")
+	}
+	return fetchPage[T](ctx, p.c, p.nextPath)
+}
+
+// Prev fetches the previous page.
+func (p *Page[T]) Prev(ctx context.Context) (*Page[T], error) {
+	if p.prevPath == "" {
+		return nil, errors.New("controller: no previous page")
+	}
+	return fetchPage[T](ctx, p.c, p.prevPath)
+}
+
+func listPage[T any](ctx context.Context, c *Client, path string, opts ListOptions) (*Page[T], error) {
+	if q := opts.query().Encode(); q != "" {
+		path += "?" + q
+	}
+	return fetchPage[T](ctx, c, path)
+}
+
+func fetchPage[T any](ctx context.Context, c *Client, path string) (*Page[T], error) {
+	var items []T
+	res, err := c.rawReq(ctx, "GET", path, nil, nil, &items)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[T]{Items: items, c: c}
+	links := parseLinkHeader(res.Header.Get("Link"))
+	if next, ok := links["next"]; ok {
+		page.nextPath = relativePath(c.url, next)
+	}
+	if prev, ok := links["prev"]; ok {
+		page.prevPath = relativePath(c.url, prev)
+	}
+	return page, nil
+}
+
+// parseLinkHeader parses a GitHub-style Link header into a rel -> URL map:
+// `<https://example.com/apps?page=2>; rel="next", <...>; rel="prev"`.
+func parseLinkHeader(header string) map[string]string {
+	links := make(map[string]string)
+	if header == "" {
+		return links
+	}
+	for _, part := range strings.Split(header, ",") {
+		segs := strings.Split(part, ";")
+		if len(segs) < 2 {
+			continue
+		}
+		url := strings.Trim(strings.TrimSpace(segs[0]), "<>")
+		for _, seg := range segs[1:] {
+			seg = strings.TrimSpace(seg)
+			if !strings.HasPrefix(seg, "rel=") {
+				continue
+			}
+			rel := strings.Trim(strings.TrimPrefix(seg, "rel="), `"`)
+			links[rel] = url
+		}
+	}
+	return links
+}
+
+// relativePath strips the client's base URL from a fully-qualified link so
+// it can be passed back through rawReq, falling back to the full link if it
+// points elsewhere (e.g. a different host behind a load balancer).
+func relativePath(base, link string) string {
+	if strings.HasPrefix(link, base) {
+		return strings.TrimPrefix(link, base)
+	}
+	return link
+}
+
+// Iterator ranges over every item of a paginated List call, fetching
+// subsequent pages transparently. Use it like bufio.Scanner:
+//
+//	it := c.Apps().Iter(ctx, controller.ListOptions{})
+//	for it.Next(ctx) {
+//		app := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	first   func(ctx context.Context) (*Page[T], error)
+	started bool
+	page    *Page[T]
+	idx     int
+	err     error
+}
+
+func newIterator[T any](first func(ctx context.Context) (*Page[T], error)) *Iterator[T] {
+	return &Iterator[T]{first: first}
+}
+
+// Next advances the iterator to the next item, fetching a new page if the
+// current one is exhausted. It returns false at the end of the result set
+// or on error; check Err to distinguish the two.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for {
+		if !it.started {
+			it.started = true
+			page, err := it.first(ctx)
+			if err != nil {
+				it.err = err
+				return false
+			}
+			it.page = page
+			it.idx = -1
+		}
+		it.idx++
+		if it.idx < len(it.page.Items) {
+			return true
+		}
+		if !it.page.HasNext() {
+			return false
+		}
+		page, err := it.page.Next(ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page
+		it.idx = -1
+	}
+}
+
+// Item returns the item at the iterator's current position. It is only
+// valid after a call to Next returns true.
+func (it *Iterator[T]) Item() T { return it.page.Items[it.idx] }
+
+// Err returns the error that stopped iteration, if any.
+func (it *Iterator[T]) Err() error { return it.err }
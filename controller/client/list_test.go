@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testItem struct {
+	ID string `json:"id"`
+}
+
+func TestListPagePaginatesViaLinkHeader(t *testing.T) {
+	pages := [][]testItem{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}},
+	}
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if r.URL.Query().Get("page") == "2" {
+			page = 2
+		}
+		if page == 1 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next"`, ts.URL))
+		} else {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=1>; rel="prev"`, ts.URL))
+		}
+		json.NewEncoder(w).Encode(pages[page-1])
+	}))
+	defer ts.Close()
+
+	c, err := NewClientWithTransport(ts.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := listPage[testItem](context.Background(), c, "/items", ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Items) != 2 || first.Items[0].ID != "1" || first.Items[1].ID != "2" {
+		t.Fatalf("unexpected first page: %+v", first.Items)
+	}
+	if !first.HasNext() || first.HasPrev() {
+		t.Fatalf("unexpected page cursors: next=%v prev=%v", first.HasNext(), first.HasPrev())
+	}
+
+	second, err := first.Next(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != "3" {
+		t.Fatalf("unexpected second page: %+v", second.Items)
+	}
+	if second.HasNext() || !second.HasPrev() {
+		t.Fatalf("unexpected page cursors: next=%v prev=%v", second.HasNext(), second.HasPrev())
+	}
+
+	back, err := second.Prev(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Items) != 2 || back.Items[0].ID != "1" {
+		t.Fatalf("unexpected page after Prev: %+v", back.Items)
+	}
+}
+
+// TestListPageNextFollowsAbsoluteLinkAcrossHosts guards against a regression
+// where a Link header pointing at a different host than c.url (e.g. behind a
+// load balancer) got concatenated onto c.url instead of being dialed
+// directly, producing a malformed URL.
+func TestListPageNextFollowsAbsoluteLinkAcrossHosts(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]testItem{{ID: "remote"}})
+	}))
+	defer other.Close()
+
+	main := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next"`, other.URL))
+		json.NewEncoder(w).Encode([]testItem{{ID: "local"}})
+	}))
+	defer main.Close()
+
+	c, err := NewClientWithTransport(main.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := listPage[testItem](context.Background(), c, "/items", ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := first.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next across hosts: %s", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != "remote" {
+		t.Fatalf("expected item from the linked host, got %+v", second.Items)
+	}
+}
+
+func TestListOptionsQuery(t *testing.T) {
+	opts := ListOptions{
+		Page:    2,
+		PerPage: 50,
+		Sort:    "-created_at",
+		Filter:  map[string]string{"release_id": "release1"},
+	}
+	q := opts.query()
+	if q.Get("page") != "2" || q.Get("per_page") != "50" || q.Get("sort") != "-created_at" {
+		t.Fatalf("unexpected query: %v", q)
+	}
+	if q.Get("filter[release_id]") != "release1" {
+		t.Fatalf("unexpected filter encoding: %v", q)
+	}
+}
+
+func TestIteratorAdvancesAcrossPages(t *testing.T) {
+	pages := [][]testItem{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}},
+	}
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if r.URL.Query().Get("page") == "2" {
+			page = 2
+		}
+		if page == 1 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next"`, ts.URL))
+		}
+		json.NewEncoder(w).Encode(pages[page-1])
+	}))
+	defer ts.Close()
+
+	c, err := NewClientWithTransport(ts.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	it := newIterator(func(ctx context.Context) (*Page[testItem], error) {
+		return listPage[testItem](ctx, c, "/items", ListOptions{})
+	})
+
+	var ids []string
+	ctx := context.Background()
+	for it.Next(ctx) {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0] != "1" || ids[1] != "2" || ids[2] != "3" {
+		t.Fatalf("unexpected items: %v", ids)
+	}
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+// ProvidersService accesses provider resources.
+type ProvidersService struct {
+	c *Client
+}
+
+// List returns a list of all providers.
+func (s *ProvidersService) List(ctx context.Context) ([]*ct.Provider, error) {
+	var providers []*ct.Provider
+	return providers, s.c.get(ctx, "/providers", &providers)
+}
+
+// ListPage returns a page of providers matching opts, along with cursors to
+// the adjacent pages.
+func (s *ProvidersService) ListPage(ctx context.Context, opts ListOptions) (*Page[*ct.Provider], error) {
+	return listPage[*ct.Provider](ctx, s.c, "/providers", opts)
+}
+
+// Iter returns an iterator over all providers matching opts, fetching
+// subsequent pages transparently as the caller advances it.
+func (s *ProvidersService) Iter(ctx context.Context, opts ListOptions) *Iterator[*ct.Provider] {
+	return newIterator(func(ctx context.Context) (*Page[*ct.Provider], error) {
+		return s.ListPage(ctx, opts)
+	})
+}
+
+// Get returns details for the specified provider.
+func (s *ProvidersService) Get(ctx context.Context, providerID string) (*ct.Provider, error) {
+	provider := &ct.Provider{}
+	return provider, s.c.get(ctx, fmt.Sprintf("/providers/%s", providerID), provider)
+}
+
+// Create creates a new provider.
+func (s *ProvidersService) Create(ctx context.Context, provider *ct.Provider) error {
+	return s.c.post(ctx, "/providers", provider, provider)
+}
+
+// Resources scopes a ResourcesService to the resources provisioned by
+// providerID.
+func (s *ProvidersService) Resources(providerID string) *ResourcesService {
+	return &ResourcesService{c: s.c, providerID: providerID}
+}
+
+// ResourcesService accesses the resources provisioned by a single provider.
+type ResourcesService struct {
+	c          *Client
+	providerID string
+}
+
+// Provision uses the provider to provision a new resource for the
+// application. Returns details about the resource.
+func (s *ResourcesService) Provision(ctx context.Context, req *ct.ResourceReq) (*ct.Resource, error) {
+	if s.providerID == "" {
+		return nil, errors.New("controller: missing provider id")
+	}
+	req.ProviderID = s.providerID
+	res := &ct.Resource{}
+	err := s.c.post(ctx, fmt.Sprintf("/providers/%s/resources", s.providerID), req, res)
+	return res, err
+}
+
+// Get returns details for the specified resource.
+func (s *ResourcesService) Get(ctx context.Context, resourceID string) (*ct.Resource, error) {
+	res := &ct.Resource{}
+	err := s.c.get(ctx, fmt.Sprintf("/providers/%s/resources/%s", s.providerID, resourceID), res)
+	return res, err
+}
+
+// Put updates a resource.
+func (s *ResourcesService) Put(ctx context.Context, resource *ct.Resource) error {
+	if resource.ID == "" {
+		return errors.New("controller: missing id")
+	}
+	resource.ProviderID = s.providerID
+	return s.c.put(ctx, fmt.Sprintf("/providers/%s/resources/%s", s.providerID, resource.ID), resource, resource)
+}
+
+// ProviderList returns a list of all providers.
+//
+// Deprecated: use Client.Providers().List instead.
+func (c *Client) ProviderList() ([]*ct.Provider, error) {
+	return c.ProviderListContext(context.Background())
+}
+
+// ProviderListContext acts like ProviderList, but bounds the request to ctx.
+//
+// Deprecated: use Client.Providers().List instead.
+func (c *Client) ProviderListContext(ctx context.Context) ([]*ct.Provider, error) {
+	return c.Providers().List(ctx)
+}
+
+// GetProvider returns details for the specified provider.
+//
+// Deprecated: use Client.Providers().Get instead.
+func (c *Client) GetProvider(providerID string) (*ct.Provider, error) {
+	return c.GetProviderContext(context.Background(), providerID)
+}
+
+// GetProviderContext acts like GetProvider, but bounds the request to ctx.
+//
+// Deprecated: use Client.Providers().Get instead.
+func (c *Client) GetProviderContext(ctx context.Context, providerID string) (*ct.Provider, error) {
+	return c.Providers().Get(ctx, providerID)
+}
+
+// CreateProvider cretes a new provider.
+//
+// Deprecated: use Client.Providers().Create instead.
+func (c *Client) CreateProvider(provider *ct.Provider) error {
+	return c.CreateProviderContext(context.Background(), provider)
+}
+
+// CreateProviderContext acts like CreateProvider, but bounds the request to ctx.
+//
+// Deprecated: use Client.Providers().Create instead.
+func (c *Client) CreateProviderContext(ctx context.Context, provider *ct.Provider) error {
+	return c.Providers().Create(ctx, provider)
+}
+
+// ProvisionResource uses a provider to provision a new resource for the
+// application. Returns details about the resource.
+//
+// Deprecated: use Client.Providers().Resources(providerID).Provision instead.
+func (c *Client) ProvisionResource(req *ct.ResourceReq) (*ct.Resource, error) {
+	return c.ProvisionResourceContext(context.Background(), req)
+}
+
+// ProvisionResourceContext acts like ProvisionResource, but bounds the
+// request to ctx.
+//
+// Deprecated: use Client.Providers().Resources(providerID).Provision instead.
+func (c *Client) ProvisionResourceContext(ctx context.Context, req *ct.ResourceReq) (*ct.Resource, error) {
+	if req.ProviderID == "" {
+		return nil, errors.New("controller: missing provider id")
+	}
+	return c.Providers().Resources(req.ProviderID).Provision(ctx, req)
+}
+
+// GetResource returns details for the specified resource.
+//
+// Deprecated: use Client.Providers().Resources(providerID).Get instead.
+func (c *Client) GetResource(providerID, resourceID string) (*ct.Resource, error) {
+	return c.GetResourceContext(context.Background(), providerID, resourceID)
+}
+
+// GetResourceContext acts like GetResource, but bounds the request to ctx.
+//
+// Deprecated: use Client.Providers().Resources(providerID).Get instead.
+func (c *Client) GetResourceContext(ctx context.Context, providerID, resourceID string) (*ct.Resource, error) {
+	return c.Providers().Resources(providerID).Get(ctx, resourceID)
+}
+
+// PutResource updates a resource.
+//
+// Deprecated: use Client.Providers().Resources(providerID).Put instead.
+func (c *Client) PutResource(resource *ct.Resource) error {
+	return c.PutResourceContext(context.Background(), resource)
+}
+
+// PutResourceContext acts like PutResource, but bounds the request to ctx.
+//
+// Deprecated: use Client.Providers().Resources(providerID).Put instead.
+func (c *Client) PutResourceContext(ctx context.Context, resource *ct.Resource) error {
+	if resource.ProviderID == "" {
+		return errors.New("controller: missing id and/or provider id")
+	}
+	return c.Providers().Resources(resource.ProviderID).Put(ctx, resource)
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+func TestProvidersServiceList(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/providers" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*ct.Provider{{ID: "provider1"}})
+	})
+
+	providers, err := c.Providers().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers) != 1 || providers[0].ID != "provider1" {
+		t.Fatalf("unexpected providers: %+v", providers)
+	}
+}
+
+func TestResourcesServiceProvision(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/providers/provider1/resources" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&ct.Resource{ID: "resource1", ProviderID: "provider1"})
+	})
+
+	res, err := c.Providers().Resources("provider1").Provision(context.Background(), &ct.ResourceReq{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != "resource1" {
+		t.Fatalf("unexpected resource: %+v", res)
+	}
+}
+
+func TestResourcesServiceProvisionRequiresProviderID(t *testing.T) {
+	c := &Client{}
+	if _, err := c.Providers().Resources("").Provision(context.Background(), &ct.ResourceReq{}); err == nil {
+		t.Fatal("expected an error when providerID is missing")
+	}
+}
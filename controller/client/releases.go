@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+// ReleasesService accesses release resources.
+type ReleasesService struct {
+	c *Client
+}
+
+// List returns all releases.
+func (s *ReleasesService) List(ctx context.Context) ([]*ct.Release, error) {
+	var releases []*ct.Release
+	return releases, s.c.get(ctx, "/releases", &releases)
+}
+
+// ListPage returns a page of releases matching opts, along with cursors to
+// the adjacent pages.
+func (s *ReleasesService) ListPage(ctx context.Context, opts ListOptions) (*Page[*ct.Release], error) {
+	return listPage[*ct.Release](ctx, s.c, "/releases", opts)
+}
+
+// Iter returns an iterator over all releases matching opts, fetching
+// subsequent pages transparently as the caller advances it.
+func (s *ReleasesService) Iter(ctx context.Context, opts ListOptions) *Iterator[*ct.Release] {
+	return newIterator(func(ctx context.Context) (*Page[*ct.Release], error) {
+		return s.ListPage(ctx, opts)
+	})
+}
+
+// Get returns details for the specified release.
+func (s *ReleasesService) Get(ctx context.Context, releaseID string) (*ct.Release, error) {
+	release := &ct.Release{}
+	return release, s.c.get(ctx, fmt.Sprintf("/releases/%s", releaseID), release)
+}
+
+// Create creates a new release.
+func (s *ReleasesService) Create(ctx context.Context, release *ct.Release) error {
+	return s.c.post(ctx, "/releases", release, release)
+}
+
+// ReleaseList returns a list of all releases.
+//
+// Deprecated: use Client.Releases().List instead.
+func (c *Client) ReleaseList() ([]*ct.Release, error) {
+	return c.ReleaseListContext(context.Background())
+}
+
+// ReleaseListContext acts like ReleaseList, but bounds the request to ctx.
+//
+// Deprecated: use Client.Releases().List instead.
+func (c *Client) ReleaseListContext(ctx context.Context) ([]*ct.Release, error) {
+	return c.Releases().List(ctx)
+}
+
+// GetRelease returns details for the specified release.
+//
+// Deprecated: use Client.Releases().Get instead.
+func (c *Client) GetRelease(releaseID string) (*ct.Release, error) {
+	return c.GetReleaseContext(context.Background(), releaseID)
+}
+
+// GetReleaseContext acts like GetRelease, but bounds the request to ctx.
+//
+// Deprecated: use Client.Releases().Get instead.
+func (c *Client) GetReleaseContext(ctx context.Context, releaseID string) (*ct.Release, error) {
+	return c.Releases().Get(ctx, releaseID)
+}
+
+// CreateRelease creates a new release.
+//
+// Deprecated: use Client.Releases().Create instead.
+func (c *Client) CreateRelease(release *ct.Release) error {
+	return c.CreateReleaseContext(context.Background(), release)
+}
+
+// CreateReleaseContext acts like CreateRelease, but bounds the request to ctx.
+//
+// Deprecated: use Client.Releases().Create instead.
+func (c *Client) CreateReleaseContext(ctx context.Context, release *ct.Release) error {
+	return c.Releases().Create(ctx, release)
+}
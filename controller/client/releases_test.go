@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ct "github.com/flynn/flynn/controller/types"
+)
+
+func TestReleasesServiceList(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/releases" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*ct.Release{{ID: "release1"}})
+	})
+
+	releases, err := c.Releases().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 1 || releases[0].ID != "release1" {
+		t.Fatalf("unexpected releases: %+v", releases)
+	}
+}
+
+func TestReleasesServiceGet(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/releases/release1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&ct.Release{ID: "release1"})
+	})
+
+	release, err := c.Releases().Get(context.Background(), "release1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.ID != "release1" {
+		t.Fatalf("unexpected release: %+v", release)
+	}
+}
+
+func TestReleasesServiceCreate(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/releases" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var release ct.Release
+		json.NewDecoder(r.Body).Decode(&release)
+		release.ID = "generated"
+		json.NewEncoder(w).Encode(&release)
+	})
+
+	release := &ct.Release{}
+	if err := c.Releases().Create(context.Background(), release); err != nil {
+		t.Fatal(err)
+	}
+	if release.ID != "generated" {
+		t.Fatalf("expected release to be populated from the response, got %+v", release)
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flynn/flynn/router/types"
+)
+
+// RoutesService accesses route resources.
+type RoutesService struct {
+	c *Client
+}
+
+// List returns all routes for an app.
+func (s *RoutesService) List(ctx context.Context, appID string) ([]*router.Route, error) {
+	var routes []*router.Route
+	return routes, s.c.get(ctx, fmt.Sprintf("/apps/%s/routes", appID), &routes)
+}
+
+// ListPage returns a page of routes for the specified app matching opts,
+// along with cursors to the adjacent pages.
+func (s *RoutesService) ListPage(ctx context.Context, appID string, opts ListOptions) (*Page[*router.Route], error) {
+	return listPage[*router.Route](ctx, s.c, fmt.Sprintf("/apps/%s/routes", appID), opts)
+}
+
+// Iter returns an iterator over all routes for the specified app matching
+// opts, fetching subsequent pages transparently as the caller advances it.
+func (s *RoutesService) Iter(ctx context.Context, appID string, opts ListOptions) *Iterator[*router.Route] {
+	return newIterator(func(ctx context.Context) (*Page[*router.Route], error) {
+		return s.ListPage(ctx, appID, opts)
+	})
+}
+
+// Get returns details for the routeID under the specified app.
+func (s *RoutesService) Get(ctx context.Context, appID, routeID string) (*router.Route, error) {
+	route := &router.Route{}
+	return route, s.c.get(ctx, fmt.Sprintf("/apps/%s/routes/%s", appID, routeID), route)
+}
+
+// Create creates a new route for the specified app.
+func (s *RoutesService) Create(ctx context.Context, appID string, route *router.Route) error {
+	return s.c.post(ctx, fmt.Sprintf("/apps/%s/routes", appID), route, route)
+}
+
+// Delete deletes a route under the specified app.
+func (s *RoutesService) Delete(ctx context.Context, appID, routeID string) error {
+	return s.c.delete(ctx, fmt.Sprintf("/apps/%s/routes/%s", appID, routeID))
+}
+
+// RouteList returns all routes for an app.
+//
+// Deprecated: use Client.Routes().List instead.
+func (c *Client) RouteList(appID string) ([]*router.Route, error) {
+	return c.RouteListContext(context.Background(), appID)
+}
+
+// RouteListContext acts like RouteList, but bounds the request to ctx.
+//
+// Deprecated: use Client.Routes().List instead.
+func (c *Client) RouteListContext(ctx context.Context, appID string) ([]*router.Route, error) {
+	return c.Routes().List(ctx, appID)
+}
+
+// GetRoute returns details for the routeID under the specified app.
+//
+// Deprecated: use Client.Routes().Get instead.
+func (c *Client) GetRoute(appID string, routeID string) (*router.Route, error) {
+	return c.GetRouteContext(context.Background(), appID, routeID)
+}
+
+// GetRouteContext acts like GetRoute, but bounds the request to ctx.
+//
+// Deprecated: use Client.Routes().Get instead.
+func (c *Client) GetRouteContext(ctx context.Context, appID string, routeID string) (*router.Route, error) {
+	return c.Routes().Get(ctx, appID, routeID)
+}
+
+// CreateRoute creates a new route for the specified app.
+//
+// Deprecated: use Client.Routes().Create instead.
+func (c *Client) CreateRoute(appID string, route *router.Route) error {
+	return c.CreateRouteContext(context.Background(), appID, route)
+}
+
+// CreateRouteContext acts like CreateRoute, but bounds the request to ctx.
+//
+// Deprecated: use Client.Routes().Create instead.
+func (c *Client) CreateRouteContext(ctx context.Context, appID string, route *router.Route) error {
+	return c.Routes().Create(ctx, appID, route)
+}
+
+// DeleteRoute deletes a route under the specified app.
+//
+// Deprecated: use Client.Routes().Delete instead.
+func (c *Client) DeleteRoute(appID string, routeID string) error {
+	return c.DeleteRouteContext(context.Background(), appID, routeID)
+}
+
+// DeleteRouteContext acts like DeleteRoute, but bounds the request to ctx.
+//
+// Deprecated: use Client.Routes().Delete instead.
+func (c *Client) DeleteRouteContext(ctx context.Context, appID string, routeID string) error {
+	return c.Routes().Delete(ctx, appID, routeID)
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/flynn/flynn/router/types"
+)
+
+func TestRoutesServiceList(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/apps/app1/routes" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]*router.Route{{ID: "route1"}})
+	})
+
+	routes, err := c.Routes().List(context.Background(), "app1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].ID != "route1" {
+		t.Fatalf("unexpected routes: %+v", routes)
+	}
+}
+
+func TestRoutesServiceDelete(t *testing.T) {
+	var deleted bool
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/apps/app1/routes/route1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		deleted = true
+	})
+
+	if err := c.Routes().Delete(context.Background(), "app1", "route1"); err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Fatal("expected DELETE request to be sent")
+	}
+}
@@ -0,0 +1,182 @@
+// Package stream provides a generic, reconnecting event stream used to
+// subscribe to controller resources (job events, formation updates, and
+// future resources such as apps, releases, and routes) over either SSE or
+// the rpcplus transport, without each call site reimplementing reconnect,
+// backoff, and resume logic.
+package stream
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff configures the delay between reconnect attempts. Each attempt
+// waits min(Max, Min*Factor^attempt), plus up to that amount again in
+// jitter, so concurrent streams against the same controller don't all
+// reconnect in lockstep.
+type Backoff struct {
+	Min    time.Duration
+	Max    time.Duration
+	Factor float64
+}
+
+// DefaultBackoff is used by New when no Backoff is given.
+var DefaultBackoff = Backoff{Min: 100 * time.Millisecond, Max: 30 * time.Second, Factor: 2}
+
+// Delay returns how long to wait before the given reconnect attempt
+// (0-indexed), including jitter. It is exported so callers building their
+// own retry loops (e.g. Client.WaitForAsyncJob) can reuse the same backoff
+// shape as EventStream.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := float64(b.Min)
+	for i := 0; i < attempt; i++ {
+		d *= b.Factor
+	}
+	max := float64(b.Max)
+	if d > max {
+		d = max
+	}
+	return time.Duration(d) + time.Duration(rand.Int63n(int64(d)+1))
+}
+
+// Filter narrows a subscription to events for a particular app and/or a set
+// of event types. The zero value matches everything.
+type Filter struct {
+	AppID string
+	Types []string
+}
+
+// Match reports whether an event with the given app ID and type passes the
+// filter.
+func (f Filter) Match(appID, eventType string) bool {
+	if f.AppID != "" && f.AppID != appID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event wraps a decoded value of type T together with the cursor it was
+// read at (an SSE Last-Event-ID for SSE-backed streams, or a `since` cursor
+// for rpcplus-backed streams), so EventStream can resume from it after a
+// dropped connection. Whether events already seen are delivered again on
+// resume depends on what cursor the Connect function reports.
+type Event[T any] struct {
+	Cursor string
+	Data   T
+}
+
+// Connect opens one connection for a subscription, decoding events onto
+// events until the connection ends or ctx is cancelled. cursor is the last
+// cursor seen ("" on the first attempt), used to resume/replay. Connect
+// returns when the underlying connection is closed (cleanly or due to
+// error); EventStream reconnects using the returned error to decide whether
+// to keep retrying.
+type Connect[T any] func(ctx context.Context, cursor string, events chan<- Event[T]) error
+
+// EventStream is a typed, resumable subscription to a sequence of events of
+// type T. It reconnects with exponential backoff and jitter whenever the
+// underlying connection drops, resuming from the last cursor it observed.
+type EventStream[T any] struct {
+	// Events delivers decoded values in order. It is closed once the
+	// stream is closed or ctx passed to New is done.
+	Events chan T
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// New starts subscribing immediately, calling connect to establish (and
+// re-establish) the underlying connection. The stream runs until ctx is
+// cancelled or Close is called.
+func New[T any](ctx context.Context, connect Connect[T], backoff Backoff) *EventStream[T] {
+	if backoff == (Backoff{}) {
+		backoff = DefaultBackoff
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &EventStream[T]{
+		Events: make(chan T),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.run(ctx, connect, backoff)
+	return s
+}
+
+func (s *EventStream[T]) run(ctx context.Context, connect Connect[T], backoff Backoff) {
+	defer close(s.done)
+	defer close(s.Events)
+
+	var cursor string
+	attempt := 0
+	for {
+		raw := make(chan Event[T])
+		connErr := make(chan error, 1)
+		go func() {
+			connErr <- connect(ctx, cursor, raw)
+			close(raw)
+		}()
+
+		for event := range raw {
+			cursor = event.Cursor
+			attempt = 0
+			select {
+			case s.Events <- event.Data:
+			case <-ctx.Done():
+				<-connErr
+				return
+			}
+		}
+
+		err := <-connErr
+		if ctx.Err() != nil {
+			if err != nil && err != ctx.Err() {
+				s.setErr(err)
+			}
+			return
+		}
+		if err != nil {
+			s.setErr(err)
+		}
+
+		select {
+		case <-time.After(backoff.Delay(attempt)):
+			attempt++
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *EventStream[T]) setErr(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// Err returns the error from the most recent failed/dropped connection, or
+// nil if the stream has not yet failed.
+func (s *EventStream[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close stops the stream and waits for its goroutine to exit.
+func (s *EventStream[T]) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
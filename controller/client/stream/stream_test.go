@@ -0,0 +1,118 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventStreamDeliversInOrder(t *testing.T) {
+	connect := func(ctx context.Context, cursor string, events chan<- Event[int]) error {
+		for i := 1; i <= 3; i++ {
+			select {
+			case events <- Event[int]{Cursor: string(rune('0' + i)), Data: i}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	s := New(context.Background(), connect, Backoff{Min: time.Millisecond, Max: time.Millisecond, Factor: 1})
+	defer s.Close()
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case got := <-s.Events:
+			if got != i {
+				t.Fatalf("got %d, want %d", got, i)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+	}
+}
+
+func TestEventStreamReconnectsAndResumesCursor(t *testing.T) {
+	var mu sync.Mutex
+	var seenCursors []string
+	attempts := 0
+
+	connect := func(ctx context.Context, cursor string, events chan<- Event[int]) error {
+		mu.Lock()
+		seenCursors = append(seenCursors, cursor)
+		attempts++
+		n := attempts
+		mu.Unlock()
+
+		if n == 1 {
+			events <- Event[int]{Cursor: "a", Data: 1}
+			return errors.New("connection dropped")
+		}
+		events <- Event[int]{Cursor: "b", Data: 2}
+		return nil
+	}
+
+	s := New(context.Background(), connect, Backoff{Min: time.Millisecond, Max: time.Millisecond, Factor: 1})
+	defer s.Close()
+
+	for i := 1; i <= 2; i++ {
+		select {
+		case got := <-s.Events:
+			if got != i {
+				t.Fatalf("got %d, want %d", got, i)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+	}
+
+	if s.Err() == nil {
+		t.Fatal("expected Err() to report the dropped connection")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seenCursors) != 2 || seenCursors[0] != "" || seenCursors[1] != "a" {
+		t.Fatalf("unexpected cursor sequence: %v", seenCursors)
+	}
+}
+
+func TestEventStreamCloseStopsReconnecting(t *testing.T) {
+	connect := func(ctx context.Context, cursor string, events chan<- Event[int]) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	s := New(context.Background(), connect, DefaultBackoff)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close returned error: %s", err)
+	}
+
+	if _, ok := <-s.Events; ok {
+		t.Fatal("expected Events to be closed")
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	cases := []struct {
+		filter    Filter
+		appID     string
+		eventType string
+		want      bool
+	}{
+		{Filter{}, "app1", "job_status", true},
+		{Filter{AppID: "app1"}, "app1", "job_status", true},
+		{Filter{AppID: "app1"}, "app2", "job_status", false},
+		{Filter{Types: []string{"job_status"}}, "app1", "job_status", true},
+		{Filter{Types: []string{"job_status"}}, "app1", "job_log", false},
+		{Filter{AppID: "app1", Types: []string{"job_status"}}, "app1", "job_log", false},
+	}
+	for _, c := range cases {
+		if got := c.filter.Match(c.appID, c.eventType); got != c.want {
+			t.Errorf("Filter(%+v).Match(%q, %q) = %v, want %v", c.filter, c.appID, c.eventType, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/flynn/flynn/controller/client/stream"
+	ct "github.com/flynn/flynn/controller/types"
+	"github.com/flynn/flynn/pkg/rpcplus"
+)
+
+// sseEventDecoder decodes one SSE event (its optional "id:" field plus its
+// "data:" field, terminated by a blank line) per Decode call, returning the
+// event id so callers can replay from it via Last-Event-ID on reconnect.
+type sseEventDecoder struct {
+	*bufio.Reader
+}
+
+func (dec *sseEventDecoder) Decode(v interface{}) (id string, err error) {
+	var data []byte
+	for {
+		line, err := dec.ReadBytes('\n')
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case bytes.HasPrefix(line, []byte("id: ")):
+			id = string(bytes.TrimSpace(bytes.TrimPrefix(line, []byte("id: "))))
+		case bytes.HasPrefix(line, []byte("data: ")):
+			data = bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data: ")))
+		case len(bytes.TrimSpace(line)) == 0 && data != nil:
+			return id, json.Unmarshal(data, v)
+		}
+	}
+}
+
+// SubscribeJobEvents returns a reconnecting, resumable stream of job events
+// for appID, optionally narrowed by filter. Unlike StreamJobEvents, it
+// automatically reconnects with backoff on a dropped connection, resuming
+// via the SSE Last-Event-ID header so events aren't missed or redelivered
+// across reconnects.
+func (c *Client) SubscribeJobEvents(ctx context.Context, appID string, filter stream.Filter) *stream.EventStream[*ct.JobEvent] {
+	connect := func(ctx context.Context, cursor string, events chan<- stream.Event[*ct.JobEvent]) error {
+		header := http.Header{"Accept": []string{"text/event-stream"}}
+		if cursor != "" {
+			header.Set("Last-Event-ID", cursor)
+		}
+		res, err := c.rawReq(ctx, "GET", fmt.Sprintf("/apps/%s/jobs", appID), header, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		dec := &sseEventDecoder{bufio.NewReader(res.Body)}
+		for {
+			event := &ct.JobEvent{}
+			id, err := dec.Decode(event)
+			if err != nil {
+				return err
+			}
+			if !filter.Match(event.AppID, string(event.Type)) {
+				continue
+			}
+			select {
+			case events <- stream.Event[*ct.JobEvent]{Cursor: id, Data: event}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return stream.New(ctx, connect, stream.DefaultBackoff)
+}
+
+// SubscribeFormations returns a reconnecting, resumable stream of formation
+// updates, optionally narrowed by filter (only filter.AppID applies here;
+// formation updates have no event type to match filter.Types against). If
+// since is not nil, only retrieves updates from since onwards on the first
+// connection; on reconnect it resumes from the time the dropped connection
+// was opened, so no update is missed, but updates already received on that
+// connection may be delivered again.
+func (c *Client) SubscribeFormations(ctx context.Context, since *time.Time, filter stream.Filter) *stream.EventStream[*ct.ExpandedFormation] {
+	start := time.Unix(0, 0)
+	if since != nil {
+		start = *since
+	}
+
+	connect := func(ctx context.Context, cursor string, events chan<- stream.Event[*ct.ExpandedFormation]) error {
+		from := start
+		if cursor != "" {
+			if t, err := time.Parse(time.RFC3339Nano, cursor); err == nil {
+				from = t
+			}
+		}
+
+		// connectedAt is captured before dialing, rather than stamping each
+		// event with its own receipt time, so that a dropped connection
+		// resumes from no later than the moment we started listening on
+		// this attempt: any update the server produced but we hadn't yet
+		// received when the connection dropped is still at or after
+		// connectedAt, so it gets redelivered on reconnect instead of
+		// silently skipped.
+		connectedAt := time.Now().UTC().Format(time.RFC3339Nano)
+
+		dial := c.dial
+		if dial == nil {
+			dial = net.Dial
+		}
+		conn, err := dial("tcp", c.addr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		stop := closeOnDone(ctx, conn)
+		defer stop()
+
+		header, err := c.authHeader()
+		if err != nil {
+			return err
+		}
+		client, err := rpcplus.NewHTTPClient(conn, rpcplus.DefaultRPCPath, header)
+		if err != nil {
+			return err
+		}
+
+		raw := make(chan *ct.ExpandedFormation)
+		call := client.StreamGo("Controller.StreamFormations", &from, raw)
+		for formation := range raw {
+			if formation.App != nil && !(stream.Filter{AppID: filter.AppID}).Match(formation.App.ID, "") {
+				continue
+			}
+			select {
+			case events <- stream.Event[*ct.ExpandedFormation]{Cursor: connectedAt, Data: formation}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return call.Error
+	}
+	return stream.New(ctx, connect, stream.DefaultBackoff)
+}
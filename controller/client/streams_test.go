@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStreamJobEventsContextReconnects guards against a regression where the
+// deprecated StreamJobEvents/StreamJobEventsContext were left as one-shot SSE
+// readers instead of being reimplemented on top of the reconnecting stream
+// subsystem: a dropped connection should not end the stream.
+func TestStreamJobEventsContextReconnects(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		n := atomic.AddInt32(&attempts, 1)
+		fmt.Fprintf(w, "id: %d\ndata: {\"job_id\":\"job%d\"}\n\n", n, n)
+		w.(http.Flusher).Flush()
+		// Drop the connection after one event so the stream must
+		// reconnect to see the next one.
+	}))
+	defer ts.Close()
+
+	c, err := NewClientWithTransport(ts.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jstream, err := c.StreamJobEventsContext(ctx, "app1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jstream.Close()
+
+	seen := make(map[string]bool)
+	for len(seen) < 2 {
+		select {
+		case event, ok := <-jstream.Events:
+			if !ok {
+				t.Fatal("Events closed before two events were seen")
+			}
+			seen[event.JobID] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for a reconnect, saw %v", seen)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// cloneRequest returns a shallow copy of req with its own Header map, so
+// RoundTrippers can set auth headers without mutating the request the
+// caller passed in (http.RoundTripper implementations must not modify the
+// original request).
+func cloneRequest(req *http.Request) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *req
+	r2.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r2.Header[k] = append([]string(nil), v...)
+	}
+	return r2
+}
+
+// Auther is implemented by RoundTrippers that can produce their auth header
+// independently of performing a full round trip, so callers that can't go
+// through http.Client.Do (the raw rpcplus dial used by
+// Formations().Subscribe, the hijacked connection used by Jobs().RunAttached)
+// can still authenticate the same way as a regular HTTP request.
+type Auther interface {
+	// SetAuth sets whatever headers are needed to authenticate req.
+	SetAuth(req *http.Request) error
+}
+
+// BasicAuthTransport is an http.RoundTripper that authenticates requests to
+// the controller API using the legacy single static key, the same as
+// req.SetBasicAuth("", key). It is the default transport used by NewClient
+// and NewClientWithPin.
+type BasicAuthTransport struct {
+	Key string
+
+	// Base is the underlying RoundTripper used to perform the request
+	// once the auth header has been set. If nil, http.DefaultTransport
+	// is used.
+	Base http.RoundTripper
+}
+
+func (t *BasicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = cloneRequest(req)
+	t.SetAuth(req)
+	return t.base().RoundTrip(req)
+}
+
+// SetAuth implements Auther.
+func (t *BasicAuthTransport) SetAuth(req *http.Request) error {
+	req.SetBasicAuth("", t.Key)
+	return nil
+}
+
+func (t *BasicAuthTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// OAuth2Transport is an http.RoundTripper that authenticates requests with
+// an OAuth2 bearer token sourced from Source, refreshing it as needed. It
+// mirrors the oauth2.Config.Client wrapping used by drone-go, letting the
+// controller client be pointed at a token from a client-credentials flow, a
+// refresh token, or any other oauth2.TokenSource.
+type OAuth2Transport struct {
+	Source oauth2.TokenSource
+
+	// Base is the underlying RoundTripper used to perform the request
+	// once the bearer token has been set. If nil, http.DefaultTransport
+	// is used.
+	Base http.RoundTripper
+}
+
+func (t *OAuth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = cloneRequest(req)
+	if err := t.SetAuth(req); err != nil {
+		return nil, err
+	}
+	return t.base().RoundTrip(req)
+}
+
+// SetAuth implements Auther.
+func (t *OAuth2Transport) SetAuth(req *http.Request) error {
+	token, err := t.Source.Token()
+	if err != nil {
+		return fmt.Errorf("controller: oauth2 token: %s", err)
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+func (t *OAuth2Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+type staticTokenSource struct{ token *oauth2.Token }
+
+func (s staticTokenSource) Token() (*oauth2.Token, error) { return s.token, nil }
+
+// TestAuthHeaderUsesTransportAuther guards against a regression where the
+// raw rpcplus dial (Formations().Subscribe) and hijacked connection
+// (Jobs().RunAttached) paths authenticated with c.key directly instead of
+// going through c.HTTP's pluggable transport, so a client built with
+// NewClientWithTransport silently sent an empty-password Basic-Auth header
+// on those two paths no matter what transport was configured.
+func TestAuthHeaderUsesTransportAuther(t *testing.T) {
+	t.Run("BasicAuthTransport", func(t *testing.T) {
+		c := &Client{HTTP: &http.Client{Transport: &BasicAuthTransport{Key: "secret"}}}
+		header, err := c.authHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		user, pass, ok := (&http.Request{Header: header}).BasicAuth()
+		if !ok || user != "" || pass != "secret" {
+			t.Fatalf("unexpected basic auth: user=%q pass=%q ok=%v", user, pass, ok)
+		}
+	})
+
+	t.Run("OAuth2Transport", func(t *testing.T) {
+		c := &Client{HTTP: &http.Client{Transport: &OAuth2Transport{
+			Source: staticTokenSource{&oauth2.Token{}},
+		}}}
+		header, err := c.authHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Get("Authorization") == "" {
+			t.Fatal("expected OAuth2Transport to set an Authorization header")
+		}
+	})
+
+	t.Run("fallback to legacy key", func(t *testing.T) {
+		c := &Client{key: "legacy", HTTP: &http.Client{Transport: http.DefaultTransport}}
+		header, err := c.authHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, pass, ok := (&http.Request{Header: header}).BasicAuth()
+		if !ok || pass != "legacy" {
+			t.Fatalf("unexpected fallback auth: pass=%q ok=%v", pass, ok)
+		}
+	})
+}